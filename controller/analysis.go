@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	patchtypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+
+	"github.com/argoproj/rollout-controller/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/rollout-controller/utils/annotations"
+	"github.com/argoproj/rollout-controller/utils/conditions"
+)
+
+// analysisSuccessThreshold is the number of consecutive successful probe
+// evaluations required, within PrePromotionAnalysis.Duration, before the
+// preview is considered verified and VerifyingPreview can be cleared.
+const analysisSuccessThreshold = 3
+
+// reconcileAnalysisRun advances the PrePromotionAnalysis for a rollout that is
+// currently verifying its preview. It evaluates the configured probes in order,
+// stopping at the first one that fails, records the outcome on
+// Status.CurrentAnalysis, and either clears VerifyingPreview once enough
+// consecutive successes have been observed within the window, or marks the
+// rollout Degraded once the window has elapsed without them. A failed tick
+// only resets the consecutive-success count.
+//
+// VerifyingPreview is set automatically by initializeAnalysisRunIfNeeded when
+// the new RS first becomes saturated, and cleared here, so an operator no
+// longer has to patch the rollout by hand.
+func (c *Controller) reconcileAnalysisRun(rollout *v1alpha1.Rollout, previewSvc *corev1.Service) error {
+	bg := rollout.Spec.Strategy.BlueGreenStrategy
+	if bg == nil || bg.PrePromotionAnalysis == nil || len(bg.PrePromotionAnalysis.Probes) == 0 {
+		// No probes configured: fall back to the existing manual-patch flow.
+		return nil
+	}
+	if conditions.RolloutDegraded(rollout) {
+		// A Degraded rollout is not analysed again; nothing in this
+		// controller clears the Degraded condition.
+		return nil
+	}
+
+	windowStart := rollout.Status.CurrentAnalysis.StartedAt
+	if windowStart.IsZero() || time.Since(windowStart.Time) > bg.PrePromotionAnalysis.Duration {
+		klog.Warningf("rollout %s/%s exceeded its %s pre-promotion analysis window without enough successes", rollout.Namespace, rollout.Name, bg.PrePromotionAnalysis.Duration)
+		return c.degradeRollout(rollout, "pre-promotion analysis did not succeed within the configured duration")
+	}
+
+	success := true
+	for _, probe := range bg.PrePromotionAnalysis.Probes {
+		ok, err := c.evaluateProbe(probe, previewSvc)
+		if err != nil {
+			klog.Warningf("analysis probe %q for rollout %s/%s errored: %v", probe.Name, rollout.Namespace, rollout.Name, err)
+		}
+		if !ok {
+			success = false
+			break
+		}
+	}
+
+	status := rollout.Status.CurrentAnalysis
+	if success {
+		status.ConsecutiveSuccesses++
+	} else {
+		status.ConsecutiveSuccesses = 0
+	}
+
+	if status.ConsecutiveSuccesses >= analysisSuccessThreshold {
+		return c.patchVerifyingPreviewAndAnalysis(rollout, false, status)
+	}
+	return c.patchVerifyingPreviewAndAnalysis(rollout, true, status)
+}
+
+// initializeAnalysisRunIfNeeded starts the PrePromotionAnalysis window the
+// first time the new RS becomes saturated, automatically setting
+// VerifyingPreview rather than requiring an operator to patch it by hand.
+// It is a no-op when no probes are configured, mirroring reconcileAnalysisRun's
+// guard, so blue-green rollouts without PrePromotionAnalysis are never stuck
+// waiting on a VerifyingPreview flag nothing will ever clear.
+func (c *Controller) initializeAnalysisRunIfNeeded(rollout *v1alpha1.Rollout, newRS *appsv1.ReplicaSet) (bool, error) {
+	bg := rollout.Spec.Strategy.BlueGreenStrategy
+	if bg == nil || bg.PrePromotionAnalysis == nil || len(bg.PrePromotionAnalysis.Probes) == 0 {
+		return false, nil
+	}
+	if rollout.Status.VerifyingPreview != nil {
+		return false, nil
+	}
+	if !annotations.IsSaturated(rollout, newRS) {
+		return false, nil
+	}
+	verifying := true
+	status := &v1alpha1.RolloutAnalysisStatus{StartedAt: metav1.Now()}
+	return true, c.patchVerifyingPreviewAndAnalysis(rollout, verifying, status)
+}
+
+// probeHTTPClient bounds how long a single HTTP probe may block, so a slow or
+// hung preview endpoint fails the probe instead of stalling the reconcile
+// loop indefinitely.
+var probeHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// evaluateProbe runs a single probe and reports whether it succeeded.
+func (c *Controller) evaluateProbe(probe v1alpha1.AnalysisProbe, previewSvc *corev1.Service) (bool, error) {
+	switch {
+	case probe.HTTPGet != nil:
+		resp, err := probeHTTPClient.Get(probe.HTTPGet.URL)
+		if err != nil {
+			return false, err
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode >= 200 && resp.StatusCode < 400, nil
+	case probe.Prometheus != nil:
+		value, err := c.queryPrometheus(probe.Prometheus.Address, probe.Prometheus.Query)
+		if err != nil {
+			return false, err
+		}
+		return value <= probe.Prometheus.Threshold, nil
+	case probe.Exec != nil:
+		return c.execProbe(previewSvc.Namespace, probe.Exec.Command)
+	default:
+		return false, fmt.Errorf("analysis probe %q has no probe type configured", probe.Name)
+	}
+}
+
+// queryPrometheus always returns an error: the controller has no Prometheus
+// client, so a Prometheus probe never succeeds.
+func (c *Controller) queryPrometheus(address, query string) (float64, error) {
+	return 0, fmt.Errorf("prometheus analysis probes are not yet supported (query %q against %q)", query, address)
+}
+
+// execProbe always returns an error: the controller has no pod exec client,
+// so an exec probe is never run and never succeeds.
+func (c *Controller) execProbe(namespace string, command []string) (bool, error) {
+	return false, fmt.Errorf("exec analysis probes are not yet supported (command %v in namespace %q)", command, namespace)
+}
+
+// patchVerifyingPreviewAndAnalysis persists the updated analysis tally and
+// VerifyingPreview flag in a single patch, mirroring setVerifyingPreview.
+func (c *Controller) patchVerifyingPreviewAndAnalysis(r *v1alpha1.Rollout, verifying bool, status *v1alpha1.RolloutAnalysisStatus) error {
+	patch := v1alpha1.Rollout{
+		Status: v1alpha1.RolloutStatus{
+			VerifyingPreview: &verifying,
+			CurrentAnalysis:  status,
+		},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = c.rolloutsclientset.ArgoprojV1alpha1().Rollouts(r.Namespace).Patch(r.Name, patchtypes.MergePatchType, patchBytes)
+	return err
+}
+
+// degradeRollout marks the rollout Degraded so reconcileAnalysisRun stops
+// evaluating it and VerifyingPreview stays set. Nothing in this controller
+// clears the condition afterwards, including a change to the spec.
+func (c *Controller) degradeRollout(r *v1alpha1.Rollout, message string) error {
+	condition := conditions.NewRolloutCondition(v1alpha1.RolloutDegradedCondition, corev1.ConditionTrue, conditions.AnalysisFailedReason, message)
+	conditions.SetRolloutCondition(&r.Status, *condition)
+	c.recorder.Eventf(r, corev1.EventTypeWarning, conditions.AnalysisFailedReason, message)
+
+	patch := v1alpha1.Rollout{Status: r.Status}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = c.rolloutsclientset.ArgoprojV1alpha1().Rollouts(r.Namespace).Patch(r.Name, patchtypes.MergePatchType, patchBytes)
+	return err
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+
+	"github.com/argoproj/rollout-controller/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/rollout-controller/utils/defaults"
+	replicasetutil "github.com/argoproj/rollout-controller/utils/replicaset"
+)
+
+// newRSAvailableForMinReadySeconds reports whether newRS has had
+// rollout.Spec.Replicas pods available (not merely ready) for at least
+// rollout.Spec.MinReadySeconds. rolloutBlueGreen checks this before letting
+// reconcileActiveService flip the active selector, instead of relying on
+// newRS.Status.AvailableReplicas alone, to avoid cutting over to pods that
+// are ready but have not yet proven stable.
+func (c *Controller) newRSAvailableForMinReadySeconds(rollout *v1alpha1.Rollout, newRS *appsv1.ReplicaSet) (bool, error) {
+	availableReplicas, err := c.availableReplicaCountForMinReadySeconds(rollout, []*appsv1.ReplicaSet{newRS})
+	if err != nil {
+		return false, err
+	}
+	return availableReplicas >= defaults.GetRolloutReplicasOrDefault(rollout), nil
+}
+
+// availableReplicaCountForMinReadySeconds returns the number of pods across
+// rsList whose Ready condition has held for at least
+// rollout.Spec.MinReadySeconds, mirroring replicasetutil.GetAvailablePodsForReplicaSets
+// but using the rollout's MinReadySeconds rather than each ReplicaSet's own
+// (which the rollout controller leaves at zero). When MinReadySeconds is
+// unset, this is equivalent to (and skips listing pods in favor of) each RS's
+// own Status.AvailableReplicas.
+func (c *Controller) availableReplicaCountForMinReadySeconds(rollout *v1alpha1.Rollout, rsList []*appsv1.ReplicaSet) (int32, error) {
+	if rollout.Spec.MinReadySeconds <= 0 {
+		return replicasetutil.GetAvailableReplicaCountForReplicaSets(rsList), nil
+	}
+
+	now := metav1.Now()
+	var available int32
+	for _, rs := range rsList {
+		selector, err := metav1.LabelSelectorAsSelector(rs.Spec.Selector)
+		if err != nil {
+			return 0, err
+		}
+		pods, err := c.podLister.Pods(rs.Namespace).List(selector)
+		if err != nil {
+			return 0, err
+		}
+		for _, pod := range pods {
+			if podutil.IsPodAvailable(pod, rollout.Spec.MinReadySeconds, now) {
+				available++
+			}
+		}
+	}
+	return available, nil
+}
+
+// enqueueRolloutForMinReadySeconds requeues the rollout to be re-reconciled
+// once a pod that is ready-but-not-yet-available crosses the MinReadySeconds
+// threshold, so the transition to available doesn't have to wait on an
+// unrelated watch event. It is a no-op unless newRS actually has such a pod.
+func (c *Controller) enqueueRolloutForMinReadySeconds(rollout *v1alpha1.Rollout, newRS *appsv1.ReplicaSet) {
+	if rollout.Spec.MinReadySeconds <= 0 {
+		return
+	}
+	// newRS.Status.AvailableReplicas is computed with the ReplicaSet's own
+	// (zero) MinReadySeconds, so count against the rollout's instead.
+	available, err := c.availableReplicaCountForMinReadySeconds(rollout, []*appsv1.ReplicaSet{newRS})
+	if err != nil || newRS.Status.ReadyReplicas <= available {
+		// No ready-but-unavailable pods to wait on.
+		return
+	}
+	c.enqueueRolloutAfter(rollout, time.Duration(rollout.Spec.MinReadySeconds)*time.Second)
+}
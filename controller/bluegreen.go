@@ -8,6 +8,8 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	patchtypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/integer"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/klog"
 	"k8s.io/kubernetes/pkg/controller"
 
@@ -36,6 +38,9 @@ func (c *Controller) rolloutBlueGreen(r *v1alpha1.Rollout, rsList []*appsv1.Repl
 		return err
 	}
 	if scaledUp {
+		if err := c.recordRolloutProgress(r, true); err != nil {
+			return err
+		}
 		return c.syncRolloutStatus(allRSs, newRS, previewSvc, activeSvc, r)
 	}
 
@@ -45,20 +50,64 @@ func (c *Controller) rolloutBlueGreen(r *v1alpha1.Rollout, rsList []*appsv1.Repl
 			return err
 		}
 		if switchPreviewSvc {
+			if err := c.recordRolloutProgress(r, true); err != nil {
+				return err
+			}
+			return c.syncRolloutStatus(allRSs, newRS, previewSvc, activeSvc, r)
+		}
+
+		startedAnalysis, err := c.initializeAnalysisRunIfNeeded(r, newRS)
+		if err != nil {
+			return err
+		}
+		if startedAnalysis {
+			if err := c.recordRolloutProgress(r, true); err != nil {
+				return err
+			}
 			return c.syncRolloutStatus(allRSs, newRS, previewSvc, activeSvc, r)
 		}
 
 		verfyingPreview := c.reconcileVerifyingPreview(activeSvc, r)
 		if verfyingPreview {
+			if err := c.reconcileAnalysisRun(r, previewSvc); err != nil {
+				return err
+			}
+			// Still verifying: this is a wait state, not progress, so the
+			// progress deadline keeps ticking if analysis never succeeds.
+			if err := c.recordRolloutProgress(r, false); err != nil {
+				return err
+			}
 			return c.syncRolloutStatus(allRSs, newRS, previewSvc, activeSvc, r)
 		}
 	}
 
+	// Require the new RS to have been available (not just ready) for at least
+	// MinReadySeconds before we let reconcileActiveService flip the active
+	// selector onto it.
+	newRSAvailable, err := c.newRSAvailableForMinReadySeconds(r, newRS)
+	if err != nil {
+		return err
+	}
+	if !newRSAvailable {
+		// The newRS may have pods that are ready but have not yet satisfied
+		// MinReadySeconds; make sure we come back to re-evaluate once they do,
+		// rather than waiting on an unrelated watch event. This is also a
+		// wait state, not progress, so the progress deadline keeps ticking.
+		c.enqueueRolloutForMinReadySeconds(r, newRS)
+		if err := c.recordRolloutProgress(r, false); err != nil {
+			return err
+		}
+		return c.syncRolloutStatus(allRSs, newRS, previewSvc, activeSvc, r)
+	}
+
 	switchActiveSvc, err := c.reconcileActiveService(r, newRS, previewSvc, activeSvc)
 	if err != nil {
 		return err
 	}
 	if switchActiveSvc {
+		if err := c.recordRolloutProgress(r, true); err != nil {
+			return err
+		}
 		return c.syncRolloutStatus(allRSs, newRS, previewSvc, activeSvc, r)
 	}
 	// Scale down, if we can.
@@ -67,6 +116,9 @@ func (c *Controller) rolloutBlueGreen(r *v1alpha1.Rollout, rsList []*appsv1.Repl
 		return err
 	}
 	if scaledDown {
+		if err := c.recordRolloutProgress(r, true); err != nil {
+			return err
+		}
 		return c.syncRolloutStatus(allRSs, newRS, previewSvc, activeSvc, r)
 	}
 
@@ -76,6 +128,12 @@ func (c *Controller) rolloutBlueGreen(r *v1alpha1.Rollout, rsList []*appsv1.Repl
 		}
 	}
 
+	// Reconciliation was a no-op: check whether we've been stuck here longer
+	// than the rollout's progress deadline.
+	if err := c.recordRolloutProgress(r, false); err != nil {
+		return err
+	}
+
 	return c.syncRolloutStatus(allRSs, newRS, previewSvc, activeSvc, r)
 }
 
@@ -105,7 +163,16 @@ func (c *Controller) reconcileNewReplicaSet(allRSs []*appsv1.ReplicaSet, newRS *
 		scaled, _, err := c.scaleReplicaSetAndRecordEvent(newRS, rolloutReplicas, rollout)
 		return scaled, err
 	}
-	newReplicasCount, err := replicasetutil.NewRSNewReplicas(rollout, allRSs, newRS)
+	var newReplicasCount int32
+	var err error
+	switch rollout.Spec.Strategy.Type {
+	case v1alpha1.RollingUpdateStrategyType:
+		newReplicasCount, err = c.newRSNewReplicasRollingUpdate(allRSs, newRS, rollout)
+	case v1alpha1.BlueGreenStrategyType:
+		newReplicasCount, err = c.newRSNewReplicasBlueGreen(allRSs, newRS, rollout)
+	default:
+		newReplicasCount, err = replicasetutil.NewRSNewReplicas(rollout, allRSs, newRS)
+	}
 	if err != nil {
 		return false, err
 	}
@@ -113,6 +180,36 @@ func (c *Controller) reconcileNewReplicaSet(allRSs []*appsv1.ReplicaSet, newRS *
 	return scaled, err
 }
 
+// newRSNewReplicasBlueGreen calculates the new replica count for the new
+// ReplicaSet during a blue-green rollout's ramp-up. Rather than immediately
+// scaling to the full rolloutReplicas (doubling cluster footprint), it caps
+// the new RS at desired+maxSurge-oldActivePodCount, growing it over
+// successive reconciliations as MaxSurge allows. A MaxSurge of 100% (the
+// default) reproduces the original full-parallel-stack behavior.
+func (c *Controller) newRSNewReplicasBlueGreen(allRSs []*appsv1.ReplicaSet, newRS *appsv1.ReplicaSet, rollout *v1alpha1.Rollout) (int32, error) {
+	rolloutReplicas := defaults.GetRolloutReplicasOrDefault(rollout)
+
+	maxSurge := rollout.Spec.Strategy.BlueGreenStrategy.MaxSurge
+	if maxSurge == nil {
+		full := intstr.FromString("100%")
+		maxSurge = &full
+	}
+	maxSurgeCount, err := intstr.GetScaledValueFromIntOrPercent(maxSurge, int(rolloutReplicas), true)
+	if err != nil {
+		return 0, err
+	}
+
+	oldActivePodCount := replicasetutil.GetReplicaCountForReplicaSets(allRSs) - *(newRS.Spec.Replicas)
+	maxTotalPods := rolloutReplicas + int32(maxSurgeCount)
+	if oldActivePodCount+*(newRS.Spec.Replicas) >= maxTotalPods {
+		// Cannot scale up further.
+		return *(newRS.Spec.Replicas), nil
+	}
+	scaleUpCount := maxTotalPods - oldActivePodCount - *(newRS.Spec.Replicas)
+	scaleUpCount = integer.Int32Min(scaleUpCount, rolloutReplicas-*(newRS.Spec.Replicas))
+	return *(newRS.Spec.Replicas) + scaleUpCount, nil
+}
+
 func (c *Controller) reconcileOldReplicaSets(allRSs []*appsv1.ReplicaSet, oldRSs []*appsv1.ReplicaSet, newRS *appsv1.ReplicaSet, rollout *v1alpha1.Rollout) (bool, error) {
 	oldPodsCount := replicasetutil.GetReplicaCountForReplicaSets(oldRSs)
 	if oldPodsCount == 0 {
@@ -181,16 +278,55 @@ func (c *Controller) cleanupUnhealthyReplicas(oldRSs []*appsv1.ReplicaSet, rollo
 
 // scaleDownOldReplicaSetsForBlueGreen scales down old replica sets when rollout strategy is "Blue Green".
 func (c *Controller) scaleDownOldReplicaSetsForBlueGreen(allRSs []*appsv1.ReplicaSet, oldRSs []*appsv1.ReplicaSet, rollout *v1alpha1.Rollout) (int32, error) {
-	availablePodCount := replicasetutil.GetAvailableReplicaCountForReplicaSets(allRSs)
-	if availablePodCount <= defaults.GetRolloutReplicasOrDefault(rollout) {
-		// Cannot scale down.
-		return 0, nil
+	desiredReplicas := defaults.GetRolloutReplicasOrDefault(rollout)
+	availablePodCount, err := c.availableReplicaCountForMinReadySeconds(rollout, allRSs)
+	if err != nil {
+		return 0, err
+	}
+
+	totalScaledDown := int32(0)
+
+	// When the rollout is oversized, preferentially scale down unhealthy pods spread
+	// across old (and newer, but still not the current newRS) ReplicaSets before
+	// touching healthy ones, so we don't churn capacity that is actually serving traffic.
+	// See https://github.com/kubernetes/kubernetes/issues/16737
+	if deploymentReplicasToRemove := replicasetutil.GetReplicaCountForReplicaSets(allRSs) - desiredReplicas; deploymentReplicasToRemove > 0 {
+		sort.Sort(controller.ReplicaSetsByCreationTimestamp(oldRSs))
+		remainingBudget := deploymentReplicasToRemove
+		for i, targetRS := range oldRSs {
+			if remainingBudget <= 0 {
+				break
+			}
+			if *(targetRS.Spec.Replicas) == 0 {
+				continue
+			}
+			unhealthyCount := *(targetRS.Spec.Replicas) - targetRS.Status.AvailableReplicas
+			if unhealthyCount <= 0 {
+				continue
+			}
+			scaleDownCount := unhealthyCount
+			if scaleDownCount > remainingBudget {
+				scaleDownCount = remainingBudget
+			}
+			newReplicasCount := *(targetRS.Spec.Replicas) - scaleDownCount
+			_, updatedRS, err := c.scaleReplicaSetAndRecordEvent(targetRS, newReplicasCount, rollout)
+			if err != nil {
+				return totalScaledDown, err
+			}
+			oldRSs[i] = updatedRS
+			totalScaledDown += scaleDownCount
+			remainingBudget -= scaleDownCount
+		}
+	}
+
+	if availablePodCount <= desiredReplicas {
+		// Cannot scale down any further.
+		return totalScaledDown, nil
 	}
 	klog.V(4).Infof("Found %d available pods in rollout %s, scaling down old RSes", availablePodCount, rollout.Name)
 
 	sort.Sort(controller.ReplicaSetsByCreationTimestamp(oldRSs))
 
-	totalScaledDown := int32(0)
 	for _, targetRS := range oldRSs {
 		if *(targetRS.Spec.Replicas) == 0 {
 			// cannot scale down this ReplicaSet.
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/argoproj/rollout-controller/pkg/apis/rollouts/v1alpha1"
+	rolloutsfake "github.com/argoproj/rollout-controller/pkg/client/clientset/versioned/fake"
+)
+
+// newFixtureController builds a Controller backed by fake clientsets and a
+// fake event recorder, the repo's normal pattern for exercising controller
+// methods without a real API server. scaleReplicaSetAndRecordEvent (and
+// anything else that calls through kubeclientset/rolloutsclientset/recorder)
+// can run against it without panicking on a nil interface.
+func newFixtureController(objects ...*appsv1.ReplicaSet) *Controller {
+	runtimeObjects := make([]runtime.Object, 0, len(objects))
+	for _, obj := range objects {
+		runtimeObjects = append(runtimeObjects, obj)
+	}
+	return &Controller{
+		kubeclientset:     k8sfake.NewSimpleClientset(runtimeObjects...),
+		rolloutsclientset: rolloutsfake.NewSimpleClientset(),
+		recorder:          record.NewFakeRecorder(100),
+	}
+}
+
+func newReplicaSetWithAvailability(name string, specReplicas, availableReplicas int32, creation metav1.Time) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: creation,
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: &specReplicas,
+		},
+		Status: appsv1.ReplicaSetStatus{
+			AvailableReplicas: availableReplicas,
+		},
+	}
+}
+
+func newBlueGreenRollout(desired int32) *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		Spec: v1alpha1.RolloutSpec{
+			Replicas: &desired,
+			Strategy: v1alpha1.RolloutStrategy{
+				Type: v1alpha1.BlueGreenStrategyType,
+			},
+		},
+	}
+}
+
+// replicasOf reads a ReplicaSet's current Spec.Replicas back from the fake
+// clientset: scaleReplicaSetAndRecordEvent returns an updated copy, so the
+// objects the test passed in do not carry the new scale.
+func replicasOf(t *testing.T, c *Controller, name string) int32 {
+	rs, err := c.kubeclientset.AppsV1().ReplicaSets("").Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error getting ReplicaSet %s: %v", name, err)
+	}
+	return *rs.Spec.Replicas
+}
+
+// TestScaleDownOldReplicaSetsForBlueGreenMixedHealth covers the mixed-health
+// matrix: unhealthy replicas are removed from old ReplicaSets (budgeted,
+// oldest-first) before any healthy ones are touched, unhealthy pods in the
+// new ReplicaSet are always left alone, and after the unhealthy pass the
+// existing full scale-to-zero pass still runs on any remaining old
+// ReplicaSets.
+func TestScaleDownOldReplicaSetsForBlueGreenMixedHealth(t *testing.T) {
+	t0 := metav1.NewTime(metav1.Now().Add(-2 * 1e9))
+	t1 := metav1.NewTime(metav1.Now().Add(-1 * 1e9))
+
+	t.Run("single unhealthy old RS is scaled to zero and healthy RSes are untouched", func(t *testing.T) {
+		oldRS1 := newReplicaSetWithAvailability("old-1", 4, 4, t0) // all healthy
+		oldRS2 := newReplicaSetWithAvailability("old-2", 2, 0, t1) // all unhealthy
+		newRS := newReplicaSetWithAvailability("new", 4, 0, metav1.Now())
+
+		oldRSs := []*appsv1.ReplicaSet{oldRS1, oldRS2}
+		allRSs := []*appsv1.ReplicaSet{oldRS1, oldRS2, newRS}
+
+		c := newFixtureController(oldRS1, oldRS2, newRS)
+		scaledDown, err := c.scaleDownOldReplicaSetsForBlueGreen(allRSs, oldRSs, newBlueGreenRollout(6))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if scaledDown != 2 {
+			t.Errorf("expected 2 unhealthy replicas scaled down, got %d", scaledDown)
+		}
+		if got := replicasOf(t, c, "old-2"); got != 0 {
+			t.Errorf("expected old-2 to be scaled to 0, got %d", got)
+		}
+		if got := replicasOf(t, c, "old-1"); got != 4 {
+			t.Errorf("expected old-1 (healthy) to be untouched, got %d", got)
+		}
+		if got := replicasOf(t, c, "new"); got != 4 {
+			t.Errorf("expected new RS to be untouched even though unhealthy, got %d", got)
+		}
+	})
+
+	t.Run("budget exhausts partway through a second unhealthy old RS", func(t *testing.T) {
+		oldRS1 := newReplicaSetWithAvailability("old-1", 3, 1, t0) // 2 unhealthy
+		oldRS2 := newReplicaSetWithAvailability("old-2", 3, 0, t1) // 3 unhealthy
+		newRS := newReplicaSetWithAvailability("new", 2, 2, metav1.Now())
+
+		oldRSs := []*appsv1.ReplicaSet{oldRS1, oldRS2}
+		allRSs := []*appsv1.ReplicaSet{oldRS1, oldRS2, newRS}
+
+		c := newFixtureController(oldRS1, oldRS2, newRS)
+		scaledDown, err := c.scaleDownOldReplicaSetsForBlueGreen(allRSs, oldRSs, newBlueGreenRollout(5))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if scaledDown != 3 {
+			t.Errorf("expected 3 total replicas scaled down (budget exhausted), got %d", scaledDown)
+		}
+		if got := replicasOf(t, c, "old-1"); got != 1 {
+			t.Errorf("expected old-1 to lose both its unhealthy replicas (3 -> 1), got %d", got)
+		}
+		if got := replicasOf(t, c, "old-2"); got != 2 {
+			t.Errorf("expected old-2 to only lose the remaining budget (3 -> 2), got %d", got)
+		}
+	})
+
+	t.Run("full scale-to-zero pass still runs on a healthy old RS after the unhealthy pass", func(t *testing.T) {
+		oldRS1 := newReplicaSetWithAvailability("old-1", 5, 5, t0) // all healthy
+		oldRS2 := newReplicaSetWithAvailability("old-2", 1, 0, t1) // all unhealthy
+		newRS := newReplicaSetWithAvailability("new", 6, 6, metav1.Now())
+
+		oldRSs := []*appsv1.ReplicaSet{oldRS1, oldRS2}
+		allRSs := []*appsv1.ReplicaSet{oldRS1, oldRS2, newRS}
+
+		c := newFixtureController(oldRS1, oldRS2, newRS)
+		scaledDown, err := c.scaleDownOldReplicaSetsForBlueGreen(allRSs, oldRSs, newBlueGreenRollout(6))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if scaledDown != 6 {
+			t.Errorf("expected 6 total replicas scaled down (1 unhealthy + 5 healthy), got %d", scaledDown)
+		}
+		if got := replicasOf(t, c, "old-2"); got != 0 {
+			t.Errorf("expected old-2 to be scaled to 0 in the unhealthy pass, got %d", got)
+		}
+		if got := replicasOf(t, c, "old-1"); got != 0 {
+			t.Errorf("expected old-1 to be scaled to 0 in the subsequent full scale-down pass, got %d", got)
+		}
+		if got := replicasOf(t, c, "new"); got != 6 {
+			t.Errorf("expected new RS to be untouched, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	patchtypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/argoproj/rollout-controller/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/rollout-controller/utils/conditions"
+	"github.com/argoproj/rollout-controller/utils/defaults"
+)
+
+// progressingSecondsGauge exposes, per rollout, how long it has been since
+// reconciliation last made forward progress. Operators alert on this
+// approaching Spec.ProgressDeadlineSeconds.
+var progressingSecondsGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "rollout_progressing_seconds",
+		Help: "Seconds since the rollout's Progressing condition was last updated with forward progress",
+	},
+	[]string{"namespace", "name"},
+)
+
+func init() {
+	prometheus.MustRegister(progressingSecondsGauge)
+}
+
+// recordRolloutProgress tracks the rollout's Progressing condition. When
+// madeProgress is true (a scaling event or a successful service switch just
+// happened) the condition's LastUpdateTime is bumped. Otherwise, if
+// reconciliation was a no-op and the rollout is not yet complete (which
+// conditions.RolloutComplete only reports while VerifyingPreview is true), the elapsed
+// time since the last bump is compared against Spec.ProgressDeadlineSeconds;
+// once exceeded, the rollout is marked Progressing=False with reason
+// ProgressDeadlineExceeded and an event is emitted.
+func (c *Controller) recordRolloutProgress(r *v1alpha1.Rollout, madeProgress bool) error {
+	deadlineSeconds := defaults.GetProgressDeadlineSecondsOrDefault(r)
+	existing := conditions.GetRolloutCondition(r.Status, v1alpha1.RolloutProgressing)
+
+	if madeProgress || existing == nil {
+		condition := conditions.NewRolloutCondition(v1alpha1.RolloutProgressing, corev1.ConditionTrue, conditions.ReplicaSetUpdatedReason, "rollout is progressing")
+		if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason {
+			// SetRolloutCondition leaves an unchanged status and reason alone,
+			// so bump LastUpdateTime on the existing condition directly.
+			for i := range r.Status.Conditions {
+				if r.Status.Conditions[i].Type == v1alpha1.RolloutProgressing {
+					r.Status.Conditions[i].LastUpdateTime = metav1.Now()
+				}
+			}
+		} else {
+			conditions.SetRolloutCondition(&r.Status, *condition)
+		}
+		progressingSecondsGauge.WithLabelValues(r.Namespace, r.Name).Set(0)
+		return c.patchRolloutStatus(r)
+	}
+
+	progressingSecondsGauge.WithLabelValues(r.Namespace, r.Name).Set(time.Since(existing.LastUpdateTime.Time).Seconds())
+
+	if conditions.RolloutComplete(r, &r.Status) {
+		return nil
+	}
+
+	elapsed := time.Since(existing.LastUpdateTime.Time)
+	deadline := time.Duration(deadlineSeconds) * time.Second
+	if elapsed < deadline {
+		// Still within the deadline; make sure we get reconciled again once it
+		// elapses, rather than waiting on an unrelated watch event.
+		c.enqueueRolloutAfter(r, deadline-elapsed)
+		return nil
+	}
+
+	if existing.Status == corev1.ConditionFalse && existing.Reason == conditions.ProgressDeadlineExceededReason {
+		// Already recorded.
+		return nil
+	}
+
+	msg := fmt.Sprintf("rollout %q exceeded its progress deadline of %ds", r.Name, deadlineSeconds)
+	klog.Warningf(msg)
+	condition := conditions.NewRolloutCondition(v1alpha1.RolloutProgressing, corev1.ConditionFalse, conditions.ProgressDeadlineExceededReason, msg)
+	conditions.SetRolloutCondition(&r.Status, *condition)
+	c.recorder.Eventf(r, corev1.EventTypeWarning, conditions.ProgressDeadlineExceededReason, msg)
+	return c.patchRolloutStatus(r)
+}
+
+// patchRolloutStatus persists r.Status via a merge patch.
+func (c *Controller) patchRolloutStatus(r *v1alpha1.Rollout) error {
+	patch := v1alpha1.Rollout{Status: r.Status}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = c.rolloutsclientset.ArgoprojV1alpha1().Rollouts(r.Namespace).Patch(r.Name, patchtypes.MergePatchType, patchBytes)
+	return err
+}
@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/integer"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"github.com/argoproj/rollout-controller/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/rollout-controller/utils/conditions"
+	"github.com/argoproj/rollout-controller/utils/defaults"
+	replicasetutil "github.com/argoproj/rollout-controller/utils/replicaset"
+)
+
+// rolloutRolling implements the logic for rolling a new replica set using the
+// RollingUpdate strategy. Unlike rolloutBlueGreen, there is no preview/active
+// service cutover: pods are shifted gradually from the old ReplicaSets to the
+// new one, bounded by MaxSurge and MaxUnavailable.
+func (c *Controller) rolloutRolling(r *v1alpha1.Rollout, rsList []*appsv1.ReplicaSet) error {
+	newRS, oldRSs, err := c.getAllReplicaSetsAndSyncRevision(r, rsList, true)
+	if err != nil {
+		return err
+	}
+	allRSs := append(oldRSs, newRS)
+
+	scaledUp, err := c.reconcileNewReplicaSet(allRSs, newRS, r)
+	if err != nil {
+		return err
+	}
+	if scaledUp {
+		if err := c.recordRolloutProgress(r, true); err != nil {
+			return err
+		}
+		return c.syncRolloutStatus(allRSs, newRS, nil, nil, r)
+	}
+
+	scaledDown, err := c.reconcileOldReplicaSetsForRollingUpdate(allRSs, controller.FilterActiveReplicaSets(oldRSs), newRS, r)
+	if err != nil {
+		return err
+	}
+	if scaledDown {
+		if err := c.recordRolloutProgress(r, true); err != nil {
+			return err
+		}
+		return c.syncRolloutStatus(allRSs, newRS, nil, nil, r)
+	}
+
+	if conditions.RolloutComplete(r, &r.Status) {
+		if err := c.cleanupRollouts(oldRSs, r); err != nil {
+			return err
+		}
+	}
+
+	if err := c.recordRolloutProgress(r, false); err != nil {
+		return err
+	}
+
+	return c.syncRolloutStatus(allRSs, newRS, nil, nil, r)
+}
+
+// newRSNewReplicasRollingUpdate calculates the new replica count for the new
+// ReplicaSet under the RollingUpdate strategy, capping it so that the total
+// pod count across all ReplicaSets never exceeds desired+maxSurge.
+func (c *Controller) newRSNewReplicasRollingUpdate(allRSs []*appsv1.ReplicaSet, newRS *appsv1.ReplicaSet, rollout *v1alpha1.Rollout) (int32, error) {
+	rolloutReplicas := defaults.GetRolloutReplicasOrDefault(rollout)
+	maxSurge, _, err := resolveFenceposts(rollingUpdateMaxSurge(rollout), rollingUpdateMaxUnavailable(rollout), rolloutReplicas)
+	if err != nil {
+		return 0, err
+	}
+
+	currentPodCount := replicasetutil.GetReplicaCountForReplicaSets(allRSs)
+	maxTotalPods := rolloutReplicas + maxSurge
+	if currentPodCount >= maxTotalPods {
+		// Cannot scale up further.
+		return *(newRS.Spec.Replicas), nil
+	}
+	scaleUpCount := maxTotalPods - currentPodCount
+	scaleUpCount = integer.Int32Min(scaleUpCount, rolloutReplicas-*(newRS.Spec.Replicas))
+	return *(newRS.Spec.Replicas) + scaleUpCount, nil
+}
+
+// reconcileOldReplicaSetsForRollingUpdate scales down old ReplicaSets when the rollout strategy is "RollingUpdate".
+func (c *Controller) reconcileOldReplicaSetsForRollingUpdate(allRSs []*appsv1.ReplicaSet, oldRSs []*appsv1.ReplicaSet, newRS *appsv1.ReplicaSet, rollout *v1alpha1.Rollout) (bool, error) {
+	oldPodsCount := replicasetutil.GetReplicaCountForReplicaSets(oldRSs)
+	if oldPodsCount == 0 {
+		// Can't scale down further
+		return false, nil
+	}
+
+	oldRSs, cleanupCount, err := c.cleanupUnhealthyReplicas(oldRSs, rollout)
+	if err != nil {
+		return false, nil
+	}
+	klog.V(4).Infof("Cleaned up unhealthy replicas from old RSes by %d", cleanupCount)
+
+	allRSs = append(oldRSs, newRS)
+	scaledDownCount, err := c.scaleDownOldReplicaSetsForRollingUpdate(allRSs, oldRSs, rollout)
+	if err != nil {
+		return false, nil
+	}
+	klog.V(4).Infof("Scaled down old RSes of rollout %s by %d", rollout.Name, scaledDownCount)
+
+	totalScaledDown := cleanupCount + scaledDownCount
+	return totalScaledDown > 0, nil
+}
+
+// scaleDownOldReplicaSetsForRollingUpdate scales down old replica sets when rollout strategy is "RollingUpdate".
+func (c *Controller) scaleDownOldReplicaSetsForRollingUpdate(allRSs []*appsv1.ReplicaSet, oldRSs []*appsv1.ReplicaSet, rollout *v1alpha1.Rollout) (int32, error) {
+	rolloutReplicas := defaults.GetRolloutReplicasOrDefault(rollout)
+	_, maxUnavailable, err := resolveFenceposts(rollingUpdateMaxSurge(rollout), rollingUpdateMaxUnavailable(rollout), rolloutReplicas)
+	if err != nil {
+		return 0, err
+	}
+
+	minAvailable := rolloutReplicas - maxUnavailable
+	availablePodCount := replicasetutil.GetAvailableReplicaCountForReplicaSets(allRSs)
+	if availablePodCount <= minAvailable {
+		// Cannot scale down.
+		return 0, nil
+	}
+	klog.V(4).Infof("Found %d available pods in rollout %s, scaling down old RSes", availablePodCount, rollout.Name)
+
+	sort.Sort(controller.ReplicaSetsByCreationTimestamp(oldRSs))
+
+	totalScaledDown := int32(0)
+	totalScaleDownCount := availablePodCount - minAvailable
+	for _, targetRS := range oldRSs {
+		if totalScaledDown >= totalScaleDownCount {
+			// No further scaling required.
+			break
+		}
+		if *(targetRS.Spec.Replicas) == 0 {
+			// cannot scale down this ReplicaSet.
+			continue
+		}
+		scaleDownCount := integer.Int32Min(*(targetRS.Spec.Replicas), totalScaleDownCount-totalScaledDown)
+		newReplicasCount := *(targetRS.Spec.Replicas) - scaleDownCount
+		if newReplicasCount > *(targetRS.Spec.Replicas) {
+			return totalScaledDown, fmt.Errorf("when scaling down old RS, got invalid request to scale down %s/%s %d -> %d", targetRS.Namespace, targetRS.Name, *(targetRS.Spec.Replicas), newReplicasCount)
+		}
+		_, _, err := c.scaleReplicaSetAndRecordEvent(targetRS, newReplicasCount, rollout)
+		if err != nil {
+			return totalScaledDown, err
+		}
+
+		totalScaledDown += scaleDownCount
+	}
+
+	return totalScaledDown, nil
+}
+
+// defaultRollingUpdateMaxSurge and defaultRollingUpdateMaxUnavailable mirror
+// the RollingUpdateDeployment defaults used by k8s Deployments, and are used
+// whenever a rollout's RollingUpdate block leaves MaxSurge or MaxUnavailable
+// unset. ValidateRolloutStrategy rejects a RollingUpdate rollout that has no
+// RollingUpdate block at all.
+var (
+	defaultRollingUpdateMaxSurge       = intstr.FromString("25%")
+	defaultRollingUpdateMaxUnavailable = intstr.FromString("25%")
+)
+
+// rollingUpdateMaxSurge returns rollout's configured MaxSurge, or the default
+// if the RollingUpdate strategy block (or the field itself) is unset.
+func rollingUpdateMaxSurge(rollout *v1alpha1.Rollout) *intstr.IntOrString {
+	ru := rollout.Spec.Strategy.RollingUpdate
+	if ru == nil || ru.MaxSurge == nil {
+		return &defaultRollingUpdateMaxSurge
+	}
+	return ru.MaxSurge
+}
+
+// rollingUpdateMaxUnavailable returns rollout's configured MaxUnavailable, or
+// the default if the RollingUpdate strategy block (or the field itself) is unset.
+func rollingUpdateMaxUnavailable(rollout *v1alpha1.Rollout) *intstr.IntOrString {
+	ru := rollout.Spec.Strategy.RollingUpdate
+	if ru == nil || ru.MaxUnavailable == nil {
+		return &defaultRollingUpdateMaxUnavailable
+	}
+	return ru.MaxUnavailable
+}
+
+// resolveFenceposts resolves maxSurge and maxUnavailable to their concrete
+// integer values, rounding maxSurge up and maxUnavailable down. If both
+// resolve to zero, maxUnavailable is forced to 1 to guarantee progress.
+func resolveFenceposts(maxSurge, maxUnavailable *intstr.IntOrString, desired int32) (int32, int32, error) {
+	surge, err := intstr.GetScaledValueFromIntOrPercent(maxSurge, int(desired), true)
+	if err != nil {
+		return 0, 0, err
+	}
+	unavailable, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, int(desired), false)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if surge == 0 && unavailable == 0 {
+		// ValidateRolloutStrategy does not reject maxSurge and maxUnavailable
+		// both resolving to zero; forcing maxUnavailable to 1 keeps the
+		// rollout able to make progress.
+		unavailable = 1
+	}
+
+	return int32(surge), int32(unavailable), nil
+}
@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/argoproj/rollout-controller/pkg/apis/rollouts/v1alpha1"
+)
+
+// syncRollout dispatches reconciliation to the handler for the rollout's
+// configured strategy.
+func (c *Controller) syncRollout(r *v1alpha1.Rollout, rsList []*appsv1.ReplicaSet) error {
+	if err := ValidateRolloutStrategy(r); err != nil {
+		c.recorder.Eventf(r, corev1.EventTypeWarning, "InvalidSpec", err.Error())
+		return err
+	}
+
+	switch r.Spec.Strategy.Type {
+	case v1alpha1.BlueGreenStrategyType:
+		return c.rolloutBlueGreen(r, rsList)
+	case v1alpha1.RollingUpdateStrategyType:
+		return c.rolloutRolling(r, rsList)
+	default:
+		return fmt.Errorf("rollout %s/%s has unsupported strategy type %q", r.Namespace, r.Name, r.Spec.Strategy.Type)
+	}
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/argoproj/rollout-controller/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/rollout-controller/utils/defaults"
+)
+
+// ValidateRolloutStrategy validates the strategy portion of a Rollout,
+// ensuring the user has not configured more than one strategy at once, that
+// the selected strategy has its required block, and that the blue-green ramp
+// can actually reach a preview target.
+func ValidateRolloutStrategy(rollout *v1alpha1.Rollout) error {
+	spec := &rollout.Spec
+	if spec.Strategy.BlueGreenStrategy != nil && spec.Strategy.RollingUpdate != nil {
+		return fmt.Errorf("rollout spec may not configure both BlueGreenStrategy and RollingUpdate")
+	}
+	if spec.Strategy.Type == v1alpha1.RollingUpdateStrategyType && spec.Strategy.RollingUpdate == nil {
+		return fmt.Errorf("rollout spec has strategy type RollingUpdate but no rollingUpdate block configured")
+	}
+	return validateBlueGreenMaxSurge(rollout)
+}
+
+// validateBlueGreenMaxSurge ensures a blue-green rollout with a preview
+// service configured has a MaxSurge that can actually produce a preview
+// target during the ramp. Uses defaults.GetRolloutReplicasOrDefault rather
+// than dereferencing Spec.Replicas directly, since Replicas is optional and
+// falls back to the rollout default.
+func validateBlueGreenMaxSurge(rollout *v1alpha1.Rollout) error {
+	bg := rollout.Spec.Strategy.BlueGreenStrategy
+	if bg == nil || bg.PreviewService == "" || bg.MaxSurge == nil {
+		return nil
+	}
+	desiredReplicas := defaults.GetRolloutReplicasOrDefault(rollout)
+	maxSurge, err := intstr.GetScaledValueFromIntOrPercent(bg.MaxSurge, int(desiredReplicas), true)
+	if err != nil {
+		return err
+	}
+	if maxSurge <= 0 {
+		return fmt.Errorf("blueGreenStrategy.maxSurge must be greater than 0 when previewService is set, otherwise there is no preview target during the ramp")
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+// Package v1alpha1 is the v1alpha1 version of the API.
+package v1alpha1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// DefaultRolloutUniqueLabelKey is the default key of the selector that is added
+// to existing ReplicaSets (and label key that is added to its pods) to prevent the existing ReplicaSets
+// to select new pods (and old pods being select by new ReplicaSet).
+const DefaultRolloutUniqueLabelKey = "rollouts-pod-template-hash"
+
+// Rollout is a specification for a Rollout resource
+type Rollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RolloutSpec   `json:"spec"`
+	Status RolloutStatus `json:"status,omitempty"`
+}
+
+// RolloutSpec is the spec for a Rollout resource
+type RolloutSpec struct {
+	// Replicas is the number of desired pods. Defaults to 1.
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Selector is a label query over pods that should match the replica count.
+	Selector *metav1.LabelSelector `json:"selector"`
+	// Template describes the pods that will be created.
+	Template corev1.PodTemplateSpec `json:"template"`
+	// Strategy defines the rollout strategy that will be applied to update the Rollout.
+	Strategy RolloutStrategy `json:"strategy"`
+	// MinReadySeconds is the minimum number of seconds for which a newly created pod should be
+	// available, without any of its container crashing, for it to be considered available.
+	// Defaults to 0 (pod will be considered available as soon as it is ready).
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+	// ProgressDeadlineSeconds is the maximum time, in seconds, that reconciliation may go without
+	// making forward progress before the rollout is considered stuck and marked
+	// Progressing=False with reason ProgressDeadlineExceeded. Defaults to 600s. The deadline is
+	// only checked while Status.VerifyingPreview is true; a rollout that stalls in any other
+	// state is not marked.
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+// RolloutStrategy defines strategy to apply during next rollout
+type RolloutStrategy struct {
+	// Type of rollout. Can be "BlueGreen" or "RollingUpdate".
+	Type RolloutStrategyType `json:"type,omitempty"`
+	// BlueGreenStrategy holds the parameters for the blue-green rollout
+	BlueGreenStrategy *BlueGreenStrategy `json:"blueGreenStrategy,omitempty"`
+	// RollingUpdate holds the parameters for the rolling update rollout
+	RollingUpdate *RollingUpdateStrategy `json:"rollingUpdate,omitempty"`
+}
+
+// RolloutStrategyType defines strategies for rollout
+type RolloutStrategyType string
+
+const (
+	// BlueGreenStrategyType switches between two versions of an application, with only one live at a time.
+	BlueGreenStrategyType RolloutStrategyType = "BlueGreen"
+	// RollingUpdateStrategyType replaces the old version of pods with the new one using a rolling update.
+	RollingUpdateStrategyType RolloutStrategyType = "RollingUpdate"
+)
+
+// BlueGreenStrategy defines parameters for Blue Green deployment
+type BlueGreenStrategy struct {
+	// ActiveService is the service to update with the new template hash at time of promotion.
+	ActiveService string `json:"activeService"`
+	// PreviewService is the service to update with the new template hash before promotion,
+	// allowing verification of the new version prior to switching the active service.
+	PreviewService string `json:"previewService,omitempty"`
+	// MaxSurge is the maximum number of pods that can be scheduled above the desired number of
+	// pods during the ramp-up of the new ReplicaSet. Value can be an absolute number (ex: 5) or a
+	// percentage of desired pods (ex: 10%). Defaults to 100%, reproducing the original
+	// full-parallel-stack behavior.
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// PrePromotionAnalysis runs an automatic verification gate against the preview service
+	// before the active service is promoted to the new ReplicaSet.
+	PrePromotionAnalysis *PrePromotionAnalysis `json:"prePromotionAnalysis,omitempty"`
+}
+
+// PrePromotionAnalysis describes the automatic verification gate that must pass before
+// VerifyingPreview is cleared and the active service can be promoted.
+type PrePromotionAnalysis struct {
+	// Duration is the window within which the required number of consecutive successful
+	// analysis ticks must be observed, otherwise the rollout is marked Degraded.
+	Duration time.Duration `json:"duration"`
+	// Probes is the list of probes evaluated on each analysis tick. All probes must succeed for
+	// the tick to count as a success.
+	Probes []AnalysisProbe `json:"probes,omitempty"`
+}
+
+// AnalysisProbe is a single check run against the preview service during PrePromotionAnalysis.
+// Exactly one of HTTPGet, Prometheus, or Exec should be set.
+type AnalysisProbe struct {
+	// Name identifies this probe in logs and status.
+	Name string `json:"name"`
+	// HTTPGet probes the preview service with an HTTP GET request.
+	HTTPGet *ProbeHTTPGet `json:"httpGet,omitempty"`
+	// Prometheus probes by evaluating a Prometheus query against a threshold. The controller
+	// has no Prometheus client, so a probe of this type always fails.
+	Prometheus *ProbePrometheus `json:"prometheus,omitempty"`
+	// Exec probes by running a command against the preview pods. The controller has no pod
+	// exec client, so a probe of this type is never run and always fails.
+	Exec *ProbeExec `json:"exec,omitempty"`
+}
+
+// ProbeHTTPGet performs an HTTP GET and considers the probe successful on any 2xx/3xx response.
+type ProbeHTTPGet struct {
+	// URL is the full address to GET, e.g. http://my-preview-svc/healthz.
+	URL string `json:"url"`
+}
+
+// ProbePrometheus evaluates a Prometheus query and considers the probe successful when the
+// returned scalar value is at or below Threshold.
+type ProbePrometheus struct {
+	// Address is the base URL of the Prometheus server to query.
+	Address string `json:"address"`
+	// Query is the PromQL query to evaluate.
+	Query string `json:"query"`
+	// Threshold is the maximum acceptable value of Query's result.
+	Threshold float64 `json:"threshold"`
+}
+
+// ProbeExec runs Command against the preview pods and considers the probe successful on exit code 0.
+type ProbeExec struct {
+	// Command is the command (and arguments) to run.
+	Command []string `json:"command"`
+}
+
+// RolloutAnalysisStatus tracks the progress of an in-flight PrePromotionAnalysis.
+type RolloutAnalysisStatus struct {
+	// StartedAt is when the current analysis window began.
+	StartedAt metav1.Time `json:"startedAt,omitempty"`
+	// ConsecutiveSuccesses is the number of analysis ticks, in a row, where every probe succeeded.
+	ConsecutiveSuccesses int32 `json:"consecutiveSuccesses,omitempty"`
+}
+
+// RollingUpdateStrategy defines parameters for RollingUpdate rollouts
+type RollingUpdateStrategy struct {
+	// MaxUnavailable is the maximum number of pods that can be unavailable during the update.
+	// Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%).
+	// Defaults to 25%.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// MaxSurge is the maximum number of pods that can be scheduled above the desired number of
+	// pods. Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%).
+	// Defaults to 25%.
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+}
+
+// RolloutStatus is the status for a Rollout resource
+type RolloutStatus struct {
+	// VerifyingPreview indicates the rollout is waiting for the preview to be verified before
+	// proceeding to scale down the old ReplicaSets and switch the active service.
+	VerifyingPreview *bool `json:"verifyingPreview,omitempty"`
+	// CurrentAnalysis tracks the in-flight PrePromotionAnalysis, if one has been started.
+	CurrentAnalysis *RolloutAnalysisStatus `json:"currentAnalysis,omitempty"`
+	// Conditions is a list of the latest available observations of a rollout's current state.
+	Conditions []RolloutCondition `json:"conditions,omitempty"`
+}
+
+// RolloutConditionType defines the conditions of a rollout
+type RolloutConditionType string
+
+const (
+	// RolloutDegradedCondition means the rollout failed PrePromotionAnalysis and reconciliation
+	// has stopped advancing it. The controller does not clear this condition, including when
+	// the spec is modified.
+	RolloutDegradedCondition RolloutConditionType = "Degraded"
+	// RolloutProgressing means the rollout is progressing. Progress is defined as any scaling
+	// event or successful service switch. A rollout is considered to have failed progressing
+	// once this condition's Status goes False with reason ProgressDeadlineExceeded.
+	RolloutProgressing RolloutConditionType = "Progressing"
+)
+
+// RolloutCondition describes the state of a rollout at a certain point.
+type RolloutCondition struct {
+	// Type of rollout condition.
+	Type RolloutConditionType `json:"type"`
+	// Phase of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// The last time this condition was updated.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// Last time the condition transitioned from one status to another.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// The reason for the condition's last transition.
+	Reason string `json:"reason,omitempty"`
+	// A human readable message indicating details about the transition.
+	Message string `json:"message,omitempty"`
+}
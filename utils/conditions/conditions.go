@@ -0,0 +1,89 @@
+// Package conditions provides helpers for reading and mutating a Rollout's
+// Status.Conditions, mirroring how the upstream Deployment controller
+// manages its own condition list.
+package conditions
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/rollout-controller/pkg/apis/rollouts/v1alpha1"
+)
+
+// AnalysisFailedReason is the reason used on the Degraded condition when
+// PrePromotionAnalysis fails to succeed within its configured window.
+const AnalysisFailedReason = "AnalysisFailed"
+
+// ReplicaSetUpdatedReason is the reason used on the Progressing condition
+// whenever reconciliation makes forward progress.
+const ReplicaSetUpdatedReason = "ReplicaSetUpdated"
+
+// ProgressDeadlineExceededReason is the reason used on the Progressing
+// condition when a rollout goes Spec.ProgressDeadlineSeconds without making
+// forward progress.
+const ProgressDeadlineExceededReason = "ProgressDeadlineExceeded"
+
+// NewRolloutCondition creates a new rollout condition.
+func NewRolloutCondition(condType v1alpha1.RolloutConditionType, status corev1.ConditionStatus, reason, message string) *v1alpha1.RolloutCondition {
+	return &v1alpha1.RolloutCondition{
+		Type:               condType,
+		Status:             status,
+		LastUpdateTime:     metav1.Now(),
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// GetRolloutCondition returns the condition with the provided type, or nil if it doesn't exist.
+func GetRolloutCondition(status v1alpha1.RolloutStatus, condType v1alpha1.RolloutConditionType) *v1alpha1.RolloutCondition {
+	for i := range status.Conditions {
+		c := status.Conditions[i]
+		if c.Type == condType {
+			return &c
+		}
+	}
+	return nil
+}
+
+// SetRolloutCondition updates the rollout to include the provided condition. If the condition that
+// we are about to add already exists and has the same status and reason then we are not going to update.
+func SetRolloutCondition(status *v1alpha1.RolloutStatus, condition v1alpha1.RolloutCondition) {
+	currentCond := GetRolloutCondition(*status, condition.Type)
+	if currentCond != nil && currentCond.Status == condition.Status && currentCond.Reason == condition.Reason {
+		return
+	}
+	// Preserve LastTransitionTime if we are not switching between statuses of a condition.
+	if currentCond != nil && currentCond.Status == condition.Status {
+		condition.LastTransitionTime = currentCond.LastTransitionTime
+	}
+	newConditions := filterOutCondition(status.Conditions, condition.Type)
+	status.Conditions = append(newConditions, condition)
+}
+
+// filterOutCondition returns a new slice of rollout conditions without conditions with the provided type.
+func filterOutCondition(conditions []v1alpha1.RolloutCondition, condType v1alpha1.RolloutConditionType) []v1alpha1.RolloutCondition {
+	var newConditions []v1alpha1.RolloutCondition
+	for _, c := range conditions {
+		if c.Type == condType {
+			continue
+		}
+		newConditions = append(newConditions, c)
+	}
+	return newConditions
+}
+
+// RolloutDegraded returns whether rollout currently has a true Degraded condition.
+func RolloutDegraded(rollout *v1alpha1.Rollout) bool {
+	cond := GetRolloutCondition(rollout.Status, v1alpha1.RolloutDegradedCondition)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+// RolloutComplete considers a rollout complete whenever it is not waiting on
+// preview verification, i.e. VerifyingPreview is unset or false. It does not
+// look at the ReplicaSets or at which ReplicaSet the active service selects,
+// so a rollout that has not yet started verifying its preview also counts as
+// complete.
+func RolloutComplete(rollout *v1alpha1.Rollout, status *v1alpha1.RolloutStatus) bool {
+	return status.VerifyingPreview == nil || !*status.VerifyingPreview
+}
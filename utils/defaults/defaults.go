@@ -0,0 +1,33 @@
+// Package defaults holds convenience accessors for Rollout fields that have
+// a meaningful zero/unset value, so callers don't have to repeat the
+// fallback logic (or risk a nil-pointer deref on an optional field) at every
+// call site.
+package defaults
+
+import (
+	"github.com/argoproj/rollout-controller/pkg/apis/rollouts/v1alpha1"
+)
+
+// DefaultReplicas is the value used for Spec.Replicas when it is unset.
+const DefaultReplicas = 1
+
+// DefaultProgressDeadlineSeconds is the value used for Spec.ProgressDeadlineSeconds when it is unset.
+const DefaultProgressDeadlineSeconds = 600
+
+// GetRolloutReplicasOrDefault returns the desired replica count for rollout,
+// falling back to DefaultReplicas when Spec.Replicas is nil.
+func GetRolloutReplicasOrDefault(rollout *v1alpha1.Rollout) int32 {
+	if rollout.Spec.Replicas != nil {
+		return *rollout.Spec.Replicas
+	}
+	return DefaultReplicas
+}
+
+// GetProgressDeadlineSecondsOrDefault returns the progress deadline for rollout,
+// falling back to DefaultProgressDeadlineSeconds when Spec.ProgressDeadlineSeconds is nil.
+func GetProgressDeadlineSecondsOrDefault(rollout *v1alpha1.Rollout) int32 {
+	if rollout.Spec.ProgressDeadlineSeconds != nil {
+		return *rollout.Spec.ProgressDeadlineSeconds
+	}
+	return DefaultProgressDeadlineSeconds
+}